@@ -0,0 +1,83 @@
+package poly
+
+import (
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// DLEQProof is a non-interactive, Fiat-Shamir batched Chaum-Pedersen proof
+// of equality of discrete logarithms. For a list of bases h_1..h_n and
+// points x_1..x_n, y_1..y_n, it attests that log_g(x_k) == log_{h_k}(y_k)
+// for every k, without revealing the shared witnesses.
+type DLEQProof struct {
+	// Challenge is the single Fiat-Shamir challenge shared by every
+	// per-index proof in the batch.
+	Challenge abstract.Secret
+
+	// Responses holds, for each index k, the prover's response r_k.
+	Responses []abstract.Secret
+}
+
+// newDLEQProof proves that, for every index k, log_g(xPoints[k]) ==
+// log_{hPoints[k]}(yPoints[k]), given the shared witnesses.
+func newDLEQProof(suite abstract.Suite, witnesses []abstract.Secret, hPoints, xPoints, yPoints []abstract.Point) (*DLEQProof, error) {
+	n := len(witnesses)
+	ws := make([]abstract.Secret, n)
+	a1 := make([]abstract.Point, n)
+	a2 := make([]abstract.Point, n)
+	for k := 0; k < n; k++ {
+		ws[k] = suite.Secret().Pick(random.Stream)
+		a1[k] = suite.Point().Mul(nil, ws[k])
+		a2[k] = suite.Point().Mul(hPoints[k], ws[k])
+	}
+
+	c := dleqChallenge(suite, a1, a2, xPoints, yPoints)
+
+	responses := make([]abstract.Secret, n)
+	for k := 0; k < n; k++ {
+		// r_k = w_k - c * witness_k
+		responses[k] = suite.Secret().Sub(ws[k], suite.Secret().Mul(c, witnesses[k]))
+	}
+
+	return &DLEQProof{Challenge: c, Responses: responses}, nil
+}
+
+// Verify checks p against the claimed equalities log_g(xPoints[k]) ==
+// log_{hPoints[k]}(yPoints[k]), using only public data.
+func (p *DLEQProof) Verify(suite abstract.Suite, hPoints, xPoints, yPoints []abstract.Point) bool {
+	n := len(p.Responses)
+	if len(hPoints) != n || len(xPoints) != n || len(yPoints) != n {
+		return false
+	}
+
+	a1 := make([]abstract.Point, n)
+	a2 := make([]abstract.Point, n)
+	for k := 0; k < n; k++ {
+		// a1_k = g^{r_k} * x_k^{c}
+		a1[k] = suite.Point().Add(
+			suite.Point().Mul(nil, p.Responses[k]),
+			suite.Point().Mul(xPoints[k], p.Challenge))
+		// a2_k = h_k^{r_k} * y_k^{c}
+		a2[k] = suite.Point().Add(
+			suite.Point().Mul(hPoints[k], p.Responses[k]),
+			suite.Point().Mul(yPoints[k], p.Challenge))
+	}
+
+	c := dleqChallenge(suite, a1, a2, xPoints, yPoints)
+	return c.Equal(p.Challenge)
+}
+
+// dleqChallenge derives the shared Fiat-Shamir challenge for a batch of
+// Chaum-Pedersen proofs by hashing the commitments a1, a2 and the points
+// xPoints, yPoints together into the suite's scalar field. The bases
+// (hPoints) are not part of the hash.
+func dleqChallenge(suite abstract.Suite, a1, a2, xPoints, yPoints []abstract.Point) abstract.Secret {
+	cipher := suite.Cipher(abstract.NoKey)
+	for _, pts := range [][]abstract.Point{a1, a2, xPoints, yPoints} {
+		for _, pt := range pts {
+			b, _ := pt.MarshalBinary()
+			cipher.Message(nil, nil, b)
+		}
+	}
+	return suite.Secret().Pick(cipher)
+}
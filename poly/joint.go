@@ -71,6 +71,19 @@ type Receiver struct {
 	// otherwise we wouldn't know which index to chose from the shared public polynomial
 	Dealers []*Dealer
 
+	// List of PVSS deals added through AddPVSSDealer. Unlike Dealers, these
+	// are trusted as soon as they're added since their DLEQ proof lets the
+	// receiver (and anyone else) verify them without a Response round-trip.
+	pvssDealers []*PVSSDeal
+
+	// receivers is the receiver list given to the most recent
+	// AddPVSSDealer call.
+	receivers []abstract.Point
+
+	// List of Feldman shares added through AddFeldmanShare. Only the shares
+	// are kept, not the FeldmanDeal each was verified against.
+	feldmanShares []Share
+
 	// When the dealers are all done, we can compute the shared secret which consists of a
 	// 1. Public Polynomial which is basically the sums of all Dealers's polynomial
 	// 2. Share of the global Private Polynomial (which is to never be computed directly), which is
@@ -133,6 +146,73 @@ func (r *Receiver) AddDealer(index int, dealer *Dealer) (*Response, error) {
 	return resp, err
 }
 
+// AddFeldmanShare verifies share against deal's Feldman commitments and, if
+// it checks out, registers it for this receiver. Like AddPVSSDealer (and
+// unlike AddDealer), this requires no Response round-trip : a FeldmanDeal
+// is self-verifying.
+func (r *Receiver) AddFeldmanShare(index int, deal *FeldmanDeal, share Share) error {
+	if r.index == -1 {
+		r.index = index
+	}
+	if r.index != index {
+		return errors.New(fmt.Sprintf("Wrong index received for receiver : %d instead of %d", index, r.index))
+	}
+	if !deal.VerifyShare(r.info.Suite, share) {
+		return errors.New(fmt.Sprintf("AddFeldmanShare : share at index %d does not verify against the deal's commitments", share.Index))
+	}
+	r.feldmanShares = append(r.feldmanShares, share)
+	return nil
+}
+
+// AddPVSSDealer registers a PVSS deal for this receiver. Because deal's DLEQ
+// proof can be checked with purely public data, this does not require a
+// Response round-trip the way AddDealer does : the deal is verified and
+// accepted (or rejected) immediately.
+func (r *Receiver) AddPVSSDealer(index int, receiverList []abstract.Point, deal *PVSSDeal) error {
+	if r.index == -1 {
+		r.index = index
+	}
+	if r.index != index {
+		return errors.New(fmt.Sprintf("Wrong index received for receiver : %d instead of %d", index, r.index))
+	}
+	if err := deal.VerifyEncryptedShares(receiverList); err != nil {
+		return err
+	}
+	r.receivers = receiverList
+	r.pvssDealers = append(r.pvssDealers, deal)
+	return nil
+}
+
+// Index returns this receiver's participant index, i.e. the same value
+// passed to AddDealer / AddPVSSDealer / AddFeldmanShare. PVSSDeal.Recover
+// keys its shares map the same way NewPVSSDealer indexed receiverList, 1
+// based, so build it with r.Index()+1 as the key for r's contribution.
+func (r *Receiver) Index() int {
+	return r.index
+}
+
+// DecryptPVSSShare decrypts and verifies this receiver's share of the
+// dealIdx-th PVSS deal registered via AddPVSSDealer. Unlike the Dealers
+// flow, a PVSS share only ever decrypts to g^p(i), not p(i) itself, so it
+// cannot be summed the way ProduceSharedSecret sums ordinary Secret shares
+// ; instead, collect DecryptPVSSShare's result from >= deal's T receivers,
+// keyed by r.Index()+1, and feed that map straight to PVSSDeal.Recover.
+func (r *Receiver) DecryptPVSSShare(dealIdx int) (abstract.Point, error) {
+	if dealIdx < 0 || dealIdx >= len(r.pvssDealers) {
+		return nil, errors.New(fmt.Sprintf("DecryptPVSSShare : deal index %d out of range", dealIdx))
+	}
+	deal := r.pvssDealers[dealIdx]
+
+	s, proof, err := deal.DecryptShare(r.index, r.Key)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Receiver %d could not decrypt its PVSS share : %v", r.index, err))
+	}
+	if err := VerifyDecryptedShare(r.info.Suite, r.Key.Public, deal.EncShares[r.index], s, proof); err != nil {
+		return nil, errors.New(fmt.Sprintf("Receiver %d's decrypted PVSS share does not verify : %v", r.index, err))
+	}
+	return s, nil
+}
+
 // ProduceSharedSecret will generate the sharedsecret relative to this receiver
 // it will throw an error if something is wrong such as not enough Dealers received
 func (r *Receiver) ProduceSharedSecret() (*SharedSecret, error) {
@@ -0,0 +1,53 @@
+package poly
+
+import (
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/random"
+)
+
+// Polynomial is a general-purpose polynomial over the suite's scalar field,
+// p(x) = sum Coeffs[j] * x^j. It underlies the lightweight Shamir/Feldman
+// primitives in shamir.go (and the PVSS scheme in pvss.go), giving
+// downstream code that needs it direct access to the committed polynomial
+// rather than only to its evaluations.
+type Polynomial struct {
+	suite  abstract.Suite
+	Coeffs []abstract.Secret
+}
+
+// NewPolynomial picks a random polynomial of degree t-1 whose constant term
+// is secret.
+func NewPolynomial(suite abstract.Suite, t int, secret abstract.Secret) *Polynomial {
+	coeffs := make([]abstract.Secret, t)
+	coeffs[0] = secret
+	for i := 1; i < t; i++ {
+		coeffs[i] = suite.Secret().Pick(random.Stream)
+	}
+	return &Polynomial{suite, coeffs}
+}
+
+// Degree returns p's degree, i.e. one less than the number of coefficients
+// needed to reconstruct it.
+func (p *Polynomial) Degree() int {
+	return len(p.Coeffs) - 1
+}
+
+// Eval returns p(i).
+func (p *Polynomial) Eval(i int) abstract.Secret {
+	xi := p.suite.Secret().SetInt64(int64(i))
+	v := p.suite.Secret().Zero()
+	for j := len(p.Coeffs) - 1; j >= 0; j-- {
+		v.Mul(v, xi)
+		v.Add(v, p.Coeffs[j])
+	}
+	return v
+}
+
+// Commit returns the Feldman commitments C_j = g^{a_j} to p's coefficients.
+func (p *Polynomial) Commit() []abstract.Point {
+	commits := make([]abstract.Point, len(p.Coeffs))
+	for j, a := range p.Coeffs {
+		commits[j] = p.suite.Point().Mul(nil, a)
+	}
+	return commits
+}
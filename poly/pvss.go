@@ -0,0 +1,168 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+)
+
+// This file implements a SCRAPE-style Publicly Verifiable Secret Sharing
+// (PVSS) scheme, as described in "SCRAPE: Scalable Randomness Attested by
+// Public Entities". Unlike the Pedersen based Dealer / Receiver flow above,
+// a PVSSDeal can be verified by any third party using only public data (the
+// participants' long term public keys and the dealer's Feldman commitments),
+// so there is no need for a Response round-trip before the deal is trusted.
+// Decryption of an individual share still requires the corresponding
+// participant's private key.
+
+// PVSSDeal holds everything a dealer publishes when running the PVSS
+// protocol for one secret.
+type PVSSDeal struct {
+	// info describes the polynomial config used for this deal
+	info PolyInfo
+
+	// Commits are the Feldman commitments C_j = g^{a_j} to the coefficients
+	// of the dealer's polynomial p.
+	Commits []abstract.Point
+
+	// EncShares holds, for each participant i, the encrypted share
+	// Y_i = pk_i^{p(i)}.
+	EncShares []abstract.Point
+
+	// Proof attests, for every participant i, that
+	// log_g(X_i) == log_{pk_i}(Y_i), where X_i = prod_j C_j^{i^j} is the
+	// evaluation of p(i) committed to in Commits. It can be checked by
+	// anyone, using only Commits, the receivers' public keys and EncShares.
+	Proof *DLEQProof
+}
+
+// commitEval publicly recomputes X_i = prod_j commits[j]^{i^j} from the
+// Feldman commitments, without needing to know p(i).
+func commitEval(suite abstract.Suite, commits []abstract.Point, i int) abstract.Point {
+	xi := suite.Secret().SetInt64(int64(i))
+	pow := suite.Secret().One()
+	x := suite.Point().Null()
+	for _, c := range commits {
+		x.Add(x, suite.Point().Mul(c, pow))
+		pow.Mul(pow, xi)
+	}
+	return x
+}
+
+// NewPVSSDealer creates a PVSSDeal for secret, sharing it among the
+// receivers listed in receiverList (indexed from 1, in order) such that any
+// t of them can later recover it.
+func NewPVSSDealer(info PolyInfo, secret abstract.Secret, receiverList []abstract.Point) (*PVSSDeal, error) {
+	if len(receiverList) != info.N {
+		return nil, errors.New(fmt.Sprintf("NewPVSSDealer : expected %d receivers, got %d", info.N, len(receiverList)))
+	}
+	poly := NewPolynomial(info.Suite, info.T, secret)
+	commits := poly.Commit()
+
+	shares := make([]abstract.Secret, info.N)
+	encShares := make([]abstract.Point, info.N)
+	xPoints := make([]abstract.Point, info.N)
+	for i := range receiverList {
+		shares[i] = poly.Eval(i + 1)
+		xPoints[i] = commitEval(info.Suite, commits, i+1)
+		encShares[i] = info.Suite.Point().Mul(receiverList[i], shares[i])
+	}
+
+	proof, err := newDLEQProof(info.Suite, shares, receiverList, xPoints, encShares)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PVSSDeal{
+		info:      info,
+		Commits:   commits,
+		EncShares: encShares,
+		Proof:     proof,
+	}, nil
+}
+
+// VerifyEncryptedShares checks, from purely public data, that every
+// encrypted share in d was correctly formed with respect to d's Feldman
+// commitments and the receivers' public keys.
+func (d *PVSSDeal) VerifyEncryptedShares(receiverList []abstract.Point) error {
+	if len(receiverList) != len(d.EncShares) {
+		return errors.New(fmt.Sprintf("VerifyEncryptedShares : expected %d shares, got %d", len(receiverList), len(d.EncShares)))
+	}
+	xPoints := make([]abstract.Point, len(receiverList))
+	for i := range receiverList {
+		xPoints[i] = commitEval(d.info.Suite, d.Commits, i+1)
+	}
+	if !d.Proof.Verify(d.info.Suite, receiverList, xPoints, d.EncShares) {
+		return errors.New("VerifyEncryptedShares : DLEQ proof does not verify")
+	}
+	return nil
+}
+
+// DecryptShare lets receiver i, holding key, recover its plaintext share
+// S_i = Y_i^{1/sk_i}, together with a proof that log_g(pk_i) == log_{S_i}(Y_i)
+// so other participants can check the decryption was done honestly.
+func (d *PVSSDeal) DecryptShare(i int, key *config.KeyPair) (abstract.Point, *DLEQProof, error) {
+	if i < 0 || i >= len(d.EncShares) {
+		return nil, nil, errors.New(fmt.Sprintf("DecryptShare : index %d out of range", i))
+	}
+	inv := d.info.Suite.Secret().Inv(key.Secret)
+	s := d.info.Suite.Point().Mul(d.EncShares[i], inv)
+
+	// Witness is sk_i : xPoints[k] = g^sk_i = pk_i, and yPoints[k] =
+	// hPoints[k]^sk_i = s^sk_i = Y_i, since s = Y_i^{1/sk_i}.
+	proof, err := newDLEQProof(d.info.Suite, []abstract.Secret{key.Secret}, []abstract.Point{s}, []abstract.Point{key.Public}, []abstract.Point{d.EncShares[i]})
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, proof, nil
+}
+
+// VerifyDecryptedShare checks that s is indeed the decryption, by the
+// holder of pub, of the encrypted share encShare, using the accompanying
+// proof produced by DecryptShare.
+func VerifyDecryptedShare(suite abstract.Suite, pub, encShare, s abstract.Point, proof *DLEQProof) error {
+	if !proof.Verify(suite, []abstract.Point{s}, []abstract.Point{pub}, []abstract.Point{encShare}) {
+		return errors.New("VerifyDecryptedShare : DLEQ proof does not verify")
+	}
+	return nil
+}
+
+// Recover reconstructs g^secret from at least t verified decrypted shares,
+// indexed the same way as in NewPVSSDealer (i.e. the share for receiverList[k]
+// must be keyed under k+1).
+func (d *PVSSDeal) Recover(shares map[int]abstract.Point) (abstract.Point, error) {
+	if len(shares) < d.info.T {
+		return nil, errors.New(fmt.Sprintf("Recover : got %d shares, need at least %d", len(shares), d.info.T))
+	}
+	present := make([]int, 0, len(shares))
+	for i := range shares {
+		present = append(present, i)
+	}
+
+	acc := d.info.Suite.Point().Null()
+	for _, i := range present {
+		l := lagrangeAt0(d.info.Suite, i, present)
+		acc.Add(acc, d.info.Suite.Point().Mul(shares[i], l))
+	}
+	return acc, nil
+}
+
+// lagrangeAt0 computes the Lagrange coefficient L_i(0) for index i (1-based)
+// with respect to the other indices in present (also 1-based).
+func lagrangeAt0(suite abstract.Suite, i int, present []int) abstract.Secret {
+	num := suite.Secret().One()
+	den := suite.Secret().One()
+	xi := suite.Secret().SetInt64(int64(i))
+	for _, j := range present {
+		if j == i {
+			continue
+		}
+		xj := suite.Secret().SetInt64(int64(j))
+		num.Mul(num, xj)
+		diff := suite.Secret().Sub(xj, xi)
+		den.Mul(den, diff)
+	}
+	return num.Div(num, den)
+}
@@ -0,0 +1,102 @@
+package poly
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/random"
+)
+
+// generatePVSSSetup creates n receiver key pairs and a PVSSDeal sharing a
+// fresh random secret among them under the given threshold.
+func generatePVSSSetup(t, n int) (abstract.Secret, []*config.KeyPair, *PVSSDeal) {
+	suite := edward
+	keys := make([]*config.KeyPair, n)
+	pubs := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		keys[i] = new(config.KeyPair)
+		keys[i].Gen(suite, random.Stream)
+		pubs[i] = keys[i].Public
+	}
+	secret := suite.Secret().Pick(random.Stream)
+	deal, err := NewPVSSDealer(PolyInfo{suite, t, n, n}, secret, pubs)
+	if err != nil {
+		panic(err)
+	}
+	return secret, keys, deal
+}
+
+func TestPVSSVerifyEncryptedShares(t *testing.T) {
+	_, keys, deal := generatePVSSSetup(3, 5)
+	pubs := make([]abstract.Point, len(keys))
+	for i, k := range keys {
+		pubs[i] = k.Public
+	}
+	if err := deal.VerifyEncryptedShares(pubs); err != nil {
+		t.Error(fmt.Sprintf("VerifyEncryptedShares should not return an error : %v", err))
+	}
+}
+
+func TestPVSSDecryptAndRecover(t *testing.T) {
+	secret, keys, deal := generatePVSSSetup(3, 5)
+
+	shares := make(map[int]abstract.Point)
+	for i := 0; i < 3; i++ {
+		s, proof, err := deal.DecryptShare(i, keys[i])
+		if err != nil {
+			t.Error(fmt.Sprintf("DecryptShare should not return an error : %v", err))
+		}
+		if err := VerifyDecryptedShare(edward, keys[i].Public, deal.EncShares[i], s, proof); err != nil {
+			t.Error(fmt.Sprintf("VerifyDecryptedShare should not return an error : %v", err))
+		}
+		shares[i+1] = s
+	}
+
+	recovered, err := deal.Recover(shares)
+	if err != nil {
+		t.Error(fmt.Sprintf("Recover should not return an error : %v", err))
+	}
+
+	expected := edward.Point().Mul(nil, secret)
+	if !recovered.Equal(expected) {
+		t.Error("Recover did not reconstruct the expected g^secret")
+	}
+}
+
+// TestPVSSReceiverFlow drives the public Receiver API end to end : each
+// participant registers the deal via AddPVSSDealer (no Response round-trip)
+// then decrypts its own share via DecryptPVSSShare, keyed by Index()+1 for
+// Recover exactly like TestPVSSDecryptAndRecover keys deal.DecryptShare's
+// result directly.
+func TestPVSSReceiverFlow(t *testing.T) {
+	secret, keys, deal := generatePVSSSetup(3, 5)
+	pubs := make([]abstract.Point, len(keys))
+	for i, k := range keys {
+		pubs[i] = k.Public
+	}
+
+	shares := make(map[int]abstract.Point)
+	for i := 0; i < 3; i++ {
+		r := NewReceiver(PolyInfo{edward, 3, 5, 5}, keys[i])
+		if err := r.AddPVSSDealer(i, pubs, deal); err != nil {
+			t.Fatal(fmt.Sprintf("AddPVSSDealer should not return an error : %v", err))
+		}
+		s, err := r.DecryptPVSSShare(0)
+		if err != nil {
+			t.Fatal(fmt.Sprintf("DecryptPVSSShare should not return an error : %v", err))
+		}
+		shares[r.Index()+1] = s
+	}
+
+	recovered, err := deal.Recover(shares)
+	if err != nil {
+		t.Error(fmt.Sprintf("Recover should not return an error : %v", err))
+	}
+	expected := edward.Point().Mul(nil, secret)
+	if !recovered.Equal(expected) {
+		t.Error("Recover did not reconstruct the expected g^secret via the Receiver flow")
+	}
+}
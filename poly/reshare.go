@@ -0,0 +1,138 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+)
+
+// This file adds proactive share refresh / resharing on top of SharedSecret.
+// Both operations reuse the existing Dealer / Receiver machinery above : a
+// ResharingSession (or a plain zero-Dealer, for Refresh) is just another
+// Promise, so new/current committee members collect them the usual way,
+// through NewReceiver / AddDealer, then combine them with CombineReshare
+// (for Reshare) or ProduceSharedSecret (for Refresh).
+
+// keyPairFromSecret wraps an already-known secret into a config.KeyPair, so
+// it can be fed to NewDealer as the value being (re-)shared.
+func keyPairFromSecret(suite abstract.Suite, s abstract.Secret) *config.KeyPair {
+	return &config.KeyPair{
+		Suite:  suite,
+		Secret: s,
+		Public: suite.Point().Mul(nil, s),
+	}
+}
+
+// ResharingSession is one current shareholder's contribution to moving a
+// SharedSecret to a new committee (of possibly different size and/or
+// threshold) without ever reconstructing the secret itself. It is just a
+// Dealer VSS-sharing a pre-weighted piece of the shareholder's own share,
+// so new committee members can recombine it with a plain, unweighted sum.
+type ResharingSession struct {
+	*Dealer
+}
+
+// Reshare re-shares ss's share to newCommittee/newT. oldIndices must list
+// the index of every old shareholder that will take part in this
+// resharing round (ss.Index among them), agreed on beforehand by all of
+// them : it fixes the Lagrange coefficient each applies to its own share,
+// so that new committee members can later recombine the resulting
+// sub-Promises with a plain sum, exactly like ProduceSharedSecret already
+// does for independent dealers.
+func (ss *SharedSecret) Reshare(suite abstract.Suite, key *config.KeyPair, oldIndices []int, newCommittee []abstract.Point, newT int) (*ResharingSession, error) {
+	found := false
+	for _, i := range oldIndices {
+		if i == ss.Index {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New(fmt.Sprintf("Reshare : index %d is not part of oldIndices", ss.Index))
+	}
+
+	// lagrangeAt0 works on 1-based x-coordinates, while share indices are
+	// 0-based : share Index is the polynomial's evaluation at Index+1.
+	xs := make([]int, len(oldIndices))
+	for k, i := range oldIndices {
+		xs[k] = i + 1
+	}
+	l := lagrangeAt0(suite, ss.Index+1, xs)
+	weighted := suite.Secret().Mul(l, *ss.Share)
+	sub := keyPairFromSecret(suite, weighted)
+
+	info := PolyInfo{Suite: suite, T: newT, R: newT, N: len(newCommittee)}
+	return &ResharingSession{NewDealer(info, sub, key, newCommittee)}, nil
+}
+
+// CombineReshare combines r's contributions from every session produced by
+// Reshare for the same resharing round into r's share of the new
+// SharedSecret. Unlike ProduceSharedSecret, which stops as soon as it has
+// info.T dealers, this requires exactly len(oldIndices) contributions :
+// Reshare pre-weights each old shareholder's sub-share by its Lagrange
+// coefficient with respect to the full oldIndices set, so the new secret
+// only reconstructs to the original one if ALL of them are summed, not just
+// a threshold-many. r must have registered exactly one Dealer per index in
+// oldIndices via AddDealer beforehand.
+func (r *Receiver) CombineReshare(oldIndices []int) (*SharedSecret, error) {
+	if len(r.Dealers) != len(oldIndices) {
+		return nil, errors.New(fmt.Sprintf("CombineReshare : need exactly %d contributions (one per index in oldIndices), got %d", len(oldIndices), len(r.Dealers)))
+	}
+
+	pub := new(PubPoly)
+	pub.InitNull(r.info.Suite, r.info.T, r.info.Suite.Point().Base())
+	share := r.info.Suite.Secret().Zero()
+
+	for _, d := range r.Dealers {
+		s, err := d.State.RevealShare(r.index, r.Key)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("CombineReshare : receiver %d could not reveal its share : %v", r.index, err))
+		}
+		share.Add(share, s)
+		pub.Add(pub, d.Promise.PubPoly())
+	}
+
+	if val := pub.Check(r.index, share); val == false {
+		return nil, errors.New("CombineReshare : receiver's combined share does not check out against the combined public polynomial")
+	}
+
+	return &SharedSecret{Pub: pub, Share: &share, Index: r.index}, nil
+}
+
+// Refresh rotates ss's share onto a freshly random polynomial, keeping the
+// committee and the threshold identical and the secret itself unchanged ;
+// it invalidates any share that may have leaked since the last refresh.
+// Every current shareholder calls Refresh and shares 0 (instead of its own
+// share) to the same committee/threshold ; once a shareholder has
+// collected t such zero-deals, folding the resulting delta SharedSecret
+// into ss via ApplyDelta yields the refreshed share.
+func (ss *SharedSecret) Refresh(suite abstract.Suite, key *config.KeyPair, t int, committee []abstract.Point) (*Dealer, error) {
+	zero := keyPairFromSecret(suite, suite.Secret().Zero())
+	info := PolyInfo{Suite: suite, T: t, R: t, N: len(committee)}
+	return NewDealer(info, zero, key, committee), nil
+}
+
+// ApplyDelta folds a delta SharedSecret, obtained the normal way from a
+// threshold t of Refresh zero-deals, into ss, producing the refreshed
+// share. Since delta shares 0, ss's and the result's group public key are
+// preserved : only PubPoly's higher coefficients change, which is the
+// point of rotating the polynomial in the first place.
+func (ss *SharedSecret) ApplyDelta(suite abstract.Suite, t int, delta *SharedSecret) (*SharedSecret, error) {
+	if delta.Index != ss.Index {
+		return nil, errors.New(fmt.Sprintf("ApplyDelta : index mismatch, %d vs %d", ss.Index, delta.Index))
+	}
+
+	share := suite.Secret().Add(*ss.Share, *delta.Share)
+	pub := new(PubPoly)
+	pub.InitNull(suite, t, suite.Point().Base())
+	pub.Add(pub, ss.Pub)
+	pub.Add(pub, delta.Pub)
+
+	if !pub.SecretCommit().Equal(ss.Pub.SecretCommit()) {
+		return nil, errors.New("ApplyDelta : refreshed group public key does not match the original one")
+	}
+
+	return &SharedSecret{Pub: pub, Share: &share, Index: ss.Index}, nil
+}
@@ -0,0 +1,179 @@
+package poly
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/random"
+)
+
+// reshareTo drives a full resharing round from an old (t,n) committee,
+// identified by its SharedSecrets and the indices taking part, to a new
+// committee of newN receivers with threshold newT, and returns the
+// resulting SharedSecret for every new receiver.
+func reshareTo(suite abstract.Suite, old []*SharedSecret, oldIndices []int, newT, newN int) []*SharedSecret {
+	newKeys := make([]*config.KeyPair, newN)
+	newPubs := make([]abstract.Point, newN)
+	for i := 0; i < newN; i++ {
+		newKeys[i] = new(config.KeyPair)
+		newKeys[i].Gen(suite, random.Stream)
+		newPubs[i] = newKeys[i].Public
+	}
+
+	signer := new(config.KeyPair)
+	signer.Gen(suite, random.Stream)
+
+	var sessions []*ResharingSession
+	for _, idx := range oldIndices {
+		session, err := old[idx].Reshare(suite, signer, oldIndices, newPubs, newT)
+		if err != nil {
+			panic(err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	results := make([]*SharedSecret, newN)
+	for k := 0; k < newN; k++ {
+		r := NewReceiver(PolyInfo{suite, newT, newT, newN}, newKeys[k])
+		for _, session := range sessions {
+			resp, err := r.AddDealer(k, session.Dealer)
+			if err != nil {
+				panic(err)
+			}
+			if err := session.AddResponse(k, resp); err != nil {
+				panic(err)
+			}
+		}
+		// Not r.ProduceSharedSecret() : Reshare's Lagrange pre-weighting
+		// only reconstructs the original secret if every one of
+		// len(oldIndices) contributions is summed, and ProduceSharedSecret
+		// stops early once it has newT of them.
+		s, err := r.CombineReshare(oldIndices)
+		if err != nil {
+			panic(err)
+		}
+		results[k] = s
+	}
+	return results
+}
+
+func TestReshareCommitteeShrink(t *testing.T) {
+	suite := edward
+	oldT, oldN := 3, 5
+	_, oldReceivers := generateNMSetup(PolyInfo{suite, oldT, oldT, oldN}, oldN, oldN)
+
+	old := make([]*SharedSecret, oldN)
+	for i, r := range oldReceivers {
+		s, err := r.ProduceSharedSecret()
+		if err != nil {
+			t.Fatal(fmt.Sprintf("ProduceSharedSecret should not error : %v", err))
+		}
+		old[i] = s
+	}
+
+	newT, newN := 2, 3
+	results := reshareTo(suite, old, []int{0, 1, 2}, newT, newN)
+
+	for i := 1; i < len(results); i++ {
+		if !results[0].Pub.Equal(results[i].Pub) {
+			t.Error("every new shareholder should agree on the same new public polynomial")
+		}
+	}
+	if !results[0].Pub.SecretCommit().Equal(old[0].Pub.SecretCommit()) {
+		t.Error("resharing must preserve the original group public key")
+	}
+}
+
+func TestReshareCommitteeGrowAndThresholdChange(t *testing.T) {
+	suite := edward
+	oldT, oldN := 2, 3
+	_, oldReceivers := generateNMSetup(PolyInfo{suite, oldT, oldT, oldN}, oldN, oldN)
+
+	old := make([]*SharedSecret, oldN)
+	for i, r := range oldReceivers {
+		s, err := r.ProduceSharedSecret()
+		if err != nil {
+			t.Fatal(fmt.Sprintf("ProduceSharedSecret should not error : %v", err))
+		}
+		old[i] = s
+	}
+
+	// All 3 old shareholders take part, so the new committee (larger, and
+	// with a higher threshold than the old one) can still be reconstructed.
+	newT, newN := 3, 7
+	results := reshareTo(suite, old, []int{0, 1, 2}, newT, newN)
+
+	for i := 1; i < len(results); i++ {
+		if !results[0].Pub.Equal(results[i].Pub) {
+			t.Error("every new shareholder should agree on the same new public polynomial")
+		}
+	}
+	if !results[0].Pub.SecretCommit().Equal(old[0].Pub.SecretCommit()) {
+		t.Error("resharing must preserve the original group public key")
+	}
+}
+
+// TestRefreshApplyDelta drives a full Refresh round : every shareholder
+// re-shares 0 to the same committee/threshold, every shareholder combines a
+// threshold of the resulting zero-deals into a delta SharedSecret, and
+// ApplyDelta folds that delta into the shareholder's existing share. The
+// group public key must come out unchanged.
+func TestRefreshApplyDelta(t *testing.T) {
+	suite := edward
+	tt, n := 3, 5
+	pubs, receivers := generateNMSetup(PolyInfo{suite, tt, tt, n}, n, n)
+
+	old := make([]*SharedSecret, n)
+	for i, r := range receivers {
+		s, err := r.ProduceSharedSecret()
+		if err != nil {
+			t.Fatal(fmt.Sprintf("ProduceSharedSecret should not error : %v", err))
+		}
+		old[i] = s
+	}
+
+	signer := new(config.KeyPair)
+	signer.Gen(suite, random.Stream)
+
+	dealers := make([]*Dealer, n)
+	for i := range old {
+		d, err := old[i].Refresh(suite, signer, tt, pubs)
+		if err != nil {
+			t.Fatal(fmt.Sprintf("Refresh should not return an error : %v", err))
+		}
+		dealers[i] = d
+	}
+
+	refreshed := make([]*SharedSecret, n)
+	for k := 0; k < n; k++ {
+		r := NewReceiver(PolyInfo{suite, tt, tt, n}, receivers[k].Key)
+		for _, d := range dealers {
+			resp, err := r.AddDealer(k, d)
+			if err != nil {
+				t.Fatal(fmt.Sprintf("AddDealer should not return an error : %v", err))
+			}
+			if err := d.AddResponse(k, resp); err != nil {
+				t.Fatal(fmt.Sprintf("AddResponse should not return an error : %v", err))
+			}
+		}
+		delta, err := r.ProduceSharedSecret()
+		if err != nil {
+			t.Fatal(fmt.Sprintf("ProduceSharedSecret should not error on the delta round : %v", err))
+		}
+		refreshed[k], err = old[k].ApplyDelta(suite, tt, delta)
+		if err != nil {
+			t.Fatal(fmt.Sprintf("ApplyDelta should not return an error : %v", err))
+		}
+	}
+
+	for i := 1; i < n; i++ {
+		if !refreshed[0].Pub.Equal(refreshed[i].Pub) {
+			t.Error("every shareholder should agree on the same refreshed public polynomial")
+		}
+	}
+	if !refreshed[0].Pub.SecretCommit().Equal(old[0].Pub.SecretCommit()) {
+		t.Error("Refresh/ApplyDelta must preserve the original group public key")
+	}
+}
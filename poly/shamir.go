@@ -0,0 +1,95 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// This file provides two lower-cost primitives that sit alongside the full
+// Pedersen Promise/Response/State machinery above, for callers that don't
+// need the extra commitment key or the Response round-trip it buys them :
+// plain Shamir secret sharing, and Feldman VSS (Shamir plus a publicly
+// checkable commitment to the sharing polynomial).
+
+// Share is a single Shamir share : the shared polynomial's evaluation at
+// Index.
+type Share struct {
+	Index int
+	Value abstract.Secret
+}
+
+// ShamirSplit splits secret into n Shares of a degree t-1 polynomial,
+// recoverable from any t of them via ShamirCombine.
+func ShamirSplit(suite abstract.Suite, secret abstract.Secret, t, n int) ([]Share, error) {
+	if t < 1 || n < t {
+		return nil, errors.New(fmt.Sprintf("ShamirSplit : invalid (t, n) = (%d, %d)", t, n))
+	}
+	p := NewPolynomial(suite, t, secret)
+	shares := make([]Share, n)
+	for i := 0; i < n; i++ {
+		shares[i] = Share{Index: i + 1, Value: p.Eval(i + 1)}
+	}
+	return shares, nil
+}
+
+// ShamirCombine reconstructs the secret shared by ShamirSplit from at least
+// t of its Shares, via Lagrange interpolation at 0.
+func ShamirCombine(suite abstract.Suite, shares []Share) (abstract.Secret, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("ShamirCombine : need at least one share")
+	}
+	present := make([]int, len(shares))
+	for i, s := range shares {
+		present[i] = s.Index
+	}
+
+	secret := suite.Secret().Zero()
+	for _, s := range shares {
+		l := lagrangeAt0(suite, s.Index, present)
+		secret.Add(secret, suite.Secret().Mul(l, s.Value))
+	}
+	return secret, nil
+}
+
+// FeldmanDeal is the result of sharing a secret with Feldman VSS : a set of
+// Shares together with public commitments to the sharing polynomial, so any
+// holder can verify its own share on its own, without an extra Pedersen
+// commitment key or a Response round-trip.
+type FeldmanDeal struct {
+	// Commits are the Feldman commitments C_j = g^{a_j} to the coefficients
+	// of the sharing polynomial.
+	Commits []abstract.Point
+	Shares  []Share
+}
+
+// NewFeldmanDeal shares secret among n participants with threshold t.
+func NewFeldmanDeal(suite abstract.Suite, secret abstract.Secret, t, n int) (*FeldmanDeal, error) {
+	if t < 1 || n < t {
+		return nil, errors.New(fmt.Sprintf("NewFeldmanDeal : invalid (t, n) = (%d, %d)", t, n))
+	}
+	p := NewPolynomial(suite, t, secret)
+	shares := make([]Share, n)
+	for i := 0; i < n; i++ {
+		shares[i] = Share{Index: i + 1, Value: p.Eval(i + 1)}
+	}
+	return &FeldmanDeal{Commits: p.Commit(), Shares: shares}, nil
+}
+
+// VerifyShare checks that share really is the evaluation, at share.Index,
+// of the polynomial committed to in d.Commits : g^share.Value ==
+// prod_j d.Commits[j]^{share.Index^j}.
+func (d *FeldmanDeal) VerifyShare(suite abstract.Suite, share Share) bool {
+	lhs := suite.Point().Mul(nil, share.Value)
+	rhs := commitEval(suite, d.Commits, share.Index)
+	return lhs.Equal(rhs)
+}
+
+// Coefficients exposes pub's underlying commitments to the shared
+// polynomial, so callers needing to operate on it directly (e.g. to cross
+// check it against a FeldmanDeal, or to recompute an evaluation) don't have
+// to go through Check.
+func (pub *PubPoly) Coefficients() []abstract.Point {
+	return pub.Commits
+}
@@ -0,0 +1,48 @@
+package poly
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/random"
+)
+
+func TestShamirSplitCombine(t *testing.T) {
+	suite := edward
+	secret := suite.Secret().Pick(random.Stream)
+
+	shares, err := ShamirSplit(suite, secret, 3, 5)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("ShamirSplit should not return an error : %v", err))
+	}
+
+	recovered, err := ShamirCombine(suite, shares[:3])
+	if err != nil {
+		t.Fatal(fmt.Sprintf("ShamirCombine should not return an error : %v", err))
+	}
+	if !recovered.Equal(secret) {
+		t.Error("ShamirCombine did not reconstruct the original secret")
+	}
+}
+
+func TestFeldmanDealVerifyShare(t *testing.T) {
+	suite := edward
+	secret := suite.Secret().Pick(random.Stream)
+
+	deal, err := NewFeldmanDeal(suite, secret, 2, 4)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("NewFeldmanDeal should not return an error : %v", err))
+	}
+
+	for _, share := range deal.Shares {
+		if !deal.VerifyShare(suite, share) {
+			t.Error("VerifyShare should accept a genuine share")
+		}
+	}
+
+	tampered := deal.Shares[0]
+	tampered.Value = suite.Secret().Pick(random.Stream)
+	if deal.VerifyShare(suite, tampered) {
+		t.Error("VerifyShare should reject a tampered share")
+	}
+}
@@ -0,0 +1,288 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/random"
+)
+
+// This file implements SyncKeyGen, a dealerless Distributed Key Generation
+// (DKG) protocol meant to run on top of an ordered broadcast (e.g. a BFT
+// log) rather than a raw network. It replaces the Dealer / AddDealer /
+// ProduceSharedSecret flow above with an explicit accept / complaint state
+// machine built out of the same Promise/State primitives : every node plays
+// dealer for its own Promise, and Handle is simply fed whatever the
+// broadcast delivers, in the order it delivers it. SyncKeyGen never starts
+// a goroutine and never touches the network itself, so callers can drive it
+// from any consensus layer they like.
+
+// MsgType identifies the kind of protocol message an OutMsg carries.
+type MsgType int
+
+const (
+	// PartMsgType marks an OutMsg carrying a Part.
+	PartMsgType MsgType = iota
+	// AckMsgType marks an OutMsg carrying an Ack.
+	AckMsgType
+	// ComplaintMsgType marks an OutMsg carrying a Complaint.
+	ComplaintMsgType
+	// JustifyMsgType marks an OutMsg carrying a Justification.
+	JustifyMsgType
+)
+
+// Part is the message a node broadcasts when it starts the protocol : its
+// own Promise, built exactly like a Dealer's, holding the Feldman/Pedersen
+// commitments to its polynomial and a share for every participant.
+type Part struct {
+	// Src is the index of the node which produced this Part.
+	Src int
+	// Promise is the node's own Promise, over the same receiver list as
+	// every other node's.
+	Promise *Promise
+}
+
+// Ack is broadcast by node Src once it has checked that its row in part
+// PartSrc matches PartSrc's commitments.
+type Ack struct {
+	// Src is the node acknowledging the Part.
+	Src int
+	// PartSrc is the index of the Part being acknowledged.
+	PartSrc int
+}
+
+// Complaint is broadcast by node Src instead of an Ack when its row in part
+// PartSrc does not verify against PartSrc's commitments. Row is revealed so
+// every other node can settle the dispute on its own, without trusting Src.
+// A Complaint never excludes a Part by itself : it only obliges PartSrc to
+// answer with a Justification, so a single dishonest Src can't get an honest
+// dealer excluded just by fabricating a Row that fails to check out.
+type Complaint struct {
+	// Src is the node complaining.
+	Src int
+	// PartSrc is the index of the accused Part.
+	PartSrc int
+	// Row is the share Src claims to have received from PartSrc.
+	Row abstract.Secret
+}
+
+// Justification is broadcast by node PartSrc in response to a Complaint
+// raised by Against, revealing PartSrc's own account of Against's row. The
+// dealer can produce this the same way a receiver reveals its own row, by
+// the Diffie-Hellman symmetry of the encryption used for each row (the
+// shared key is derivable from either side's private key and the other's
+// public key). This settles the dispute against the dealer's own claim
+// rather than the complainer's.
+type Justification struct {
+	// PartSrc is the index of the Part being justified.
+	PartSrc int
+	// Against is the index of the row in dispute, i.e. the complainer's.
+	Against int
+	// Row is PartSrc's own account of the disputed row.
+	Row abstract.Secret
+}
+
+// OutMsg is the envelope SyncKeyGen uses for every message it needs
+// broadcast; exactly one of Part, Ack, Complaint or Justification is set,
+// per Type.
+type OutMsg struct {
+	Type          MsgType
+	Part          *Part
+	Ack           *Ack
+	Complaint     *Complaint
+	Justification *Justification
+}
+
+// SyncKeyGen drives the dealerless DKG described above. Feed it messages as
+// the broadcast delivers them with Handle, use IsReady to know when enough
+// Parts have been accepted, and Finalize to get the resulting SharedSecret.
+type SyncKeyGen struct {
+	info PolyInfo
+	// index is this node's own index, both as dealer and as receiver.
+	index int
+	key   *config.KeyPair
+	pub   []abstract.Point
+
+	states map[int]*State // one State per received Part, keyed by Src
+
+	acks       map[int]map[int]bool // acks[partSrc][acker]
+	complaints map[int]*Complaint   // complaints[partSrc], first one wins
+	excluded   map[int]bool         // partSrc -> proven invalid
+}
+
+// NewSyncKeyGen creates a SyncKeyGen for node index, with key as its
+// long-term key pair and pub the long-term public keys of every
+// participant (including this node, at position index).
+func NewSyncKeyGen(info PolyInfo, key *config.KeyPair, index int, pub []abstract.Point) *SyncKeyGen {
+	return &SyncKeyGen{
+		info:       info,
+		index:      index,
+		key:        key,
+		pub:        pub,
+		states:     make(map[int]*State),
+		acks:       make(map[int]map[int]bool),
+		complaints: make(map[int]*Complaint),
+		excluded:   make(map[int]bool),
+	}
+}
+
+// Start generates this node's own Promise and feeds it back through Handle,
+// returning the Part and the resulting self-Ack (or self-Complaint) to
+// broadcast to the rest of the group. The self-Ack must be broadcast along
+// with the Part : handlePart never acks a Part on behalf of any node but the
+// one processing it, so without it, node index's own Part could never
+// collect more than n-1 Acks and IsReady/Finalize's T+1 threshold would be
+// unreachable whenever n == T+1.
+func (s *SyncKeyGen) Start() ([]*OutMsg, error) {
+	secret := new(config.KeyPair)
+	secret.Gen(s.info.Suite, random.Stream)
+	promise := new(Promise).ConstructPromise(secret, s.key, s.info.T, s.info.R, s.pub)
+	part := &Part{Src: s.index, Promise: promise}
+	partMsg := &OutMsg{Type: PartMsgType, Part: part}
+
+	out, err := s.Handle(partMsg)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*OutMsg{partMsg}, out...), nil
+}
+
+// Handle processes one incoming protocol message and returns the messages
+// this node needs to broadcast in response, if any.
+func (s *SyncKeyGen) Handle(msg *OutMsg) ([]*OutMsg, error) {
+	switch msg.Type {
+	case PartMsgType:
+		return s.handlePart(msg.Part)
+	case AckMsgType:
+		return s.handleAck(msg.Ack)
+	case ComplaintMsgType:
+		return s.handleComplaint(msg.Complaint)
+	case JustifyMsgType:
+		return s.handleJustify(msg.Justification)
+	default:
+		return nil, errors.New(fmt.Sprintf("SyncKeyGen.Handle : unknown message type %d", msg.Type))
+	}
+}
+
+func (s *SyncKeyGen) handlePart(part *Part) ([]*OutMsg, error) {
+	if _, ok := s.states[part.Src]; ok {
+		return nil, errors.New(fmt.Sprintf("SyncKeyGen : already have a Part from node %d", part.Src))
+	}
+	state := new(State).Init(*part.Promise)
+	s.states[part.Src] = state
+
+	row, err := state.RevealShare(s.index, s.key)
+	if err != nil {
+		if row == nil {
+			// Nothing to put in a Complaint's Row ; broadcasting one would
+			// just panic every node that tries to serialize it. This means
+			// we can't accuse PartSrc with anything other nodes could
+			// verify, so surface the failure to the caller instead.
+			return nil, errors.New(fmt.Sprintf("SyncKeyGen : could not reveal share of Part %d to raise a Complaint : %v", part.Src, err))
+		}
+		c := &Complaint{Src: s.index, PartSrc: part.Src, Row: row}
+		return []*OutMsg{{Type: ComplaintMsgType, Complaint: c}}, nil
+	}
+
+	a := &Ack{Src: s.index, PartSrc: part.Src}
+	return []*OutMsg{{Type: AckMsgType, Ack: a}}, nil
+}
+
+func (s *SyncKeyGen) handleAck(ack *Ack) ([]*OutMsg, error) {
+	if ack.Src < 0 || ack.Src >= len(s.pub) {
+		return nil, errors.New(fmt.Sprintf("SyncKeyGen : Ack from out-of-range index %d", ack.Src))
+	}
+	if s.acks[ack.PartSrc] == nil {
+		s.acks[ack.PartSrc] = make(map[int]bool)
+	}
+	s.acks[ack.PartSrc][ack.Src] = true
+	return nil, nil
+}
+
+func (s *SyncKeyGen) handleComplaint(c *Complaint) ([]*OutMsg, error) {
+	state, ok := s.states[c.PartSrc]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("SyncKeyGen : complaint about unknown Part %d", c.PartSrc))
+	}
+	if _, ok := s.complaints[c.PartSrc]; ok {
+		return nil, nil
+	}
+	s.complaints[c.PartSrc] = c
+
+	if s.index != c.PartSrc {
+		// Only the accused dealer can settle this : trusting c.Row at face
+		// value would let any single node get an honest Part excluded just
+		// by fabricating a Row that fails PubPoly().Check. Wait for PartSrc's
+		// own Justification instead.
+		return nil, nil
+	}
+
+	row, err := state.RevealShare(c.Src, s.key)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("SyncKeyGen : could not reveal row %d of own Part to answer a Complaint : %v", c.Src, err))
+	}
+	j := &Justification{PartSrc: c.PartSrc, Against: c.Src, Row: row}
+	return []*OutMsg{{Type: JustifyMsgType, Justification: j}}, nil
+}
+
+// handleJustify settles a Complaint using the accused dealer's own account
+// of the disputed row : the Part is excluded only if the dealer's own
+// Justification fails to check out, never on the complainer's claim alone.
+func (s *SyncKeyGen) handleJustify(j *Justification) ([]*OutMsg, error) {
+	state, ok := s.states[j.PartSrc]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("SyncKeyGen : justification for unknown Part %d", j.PartSrc))
+	}
+	if !state.Promise.PubPoly().Check(j.Against, j.Row) {
+		s.excluded[j.PartSrc] = true
+	}
+	return nil, nil
+}
+
+// IsReady reports whether a threshold of Parts have each gathered at least
+// T+1 Acks and carry no founded complaint.
+func (s *SyncKeyGen) IsReady() bool {
+	good := 0
+	for src := range s.states {
+		if s.excluded[src] {
+			continue
+		}
+		if len(s.acks[src]) >= s.info.T+1 {
+			good++
+		}
+	}
+	return good >= s.info.T+1
+}
+
+// Finalize computes the group public key Y = prod_i C_{i,0} and this node's
+// share = sum_i s_{i,index}, once IsReady reports true.
+func (s *SyncKeyGen) Finalize() (*SharedSecret, error) {
+	if !s.IsReady() {
+		return nil, errors.New("SyncKeyGen : not ready, not enough acknowledged Parts yet")
+	}
+
+	pub := new(PubPoly)
+	pub.InitNull(s.info.Suite, s.info.T, s.info.Suite.Point().Base())
+	share := s.info.Suite.Secret().Zero()
+
+	good := 0
+	for src, state := range s.states {
+		if s.excluded[src] || len(s.acks[src]) < s.info.T+1 {
+			continue
+		}
+		row, err := state.RevealShare(s.index, s.key)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("SyncKeyGen : could not reveal own share of Part %d at Finalize time : %v", src, err))
+		}
+		share.Add(share, row)
+		pub.Add(pub, state.Promise.PubPoly())
+		good++
+	}
+	if good < s.info.T+1 {
+		return nil, errors.New("SyncKeyGen : lost too many accepted Parts since IsReady was last checked")
+	}
+
+	return &SharedSecret{Pub: pub, Share: &share, Index: s.index}, nil
+}
@@ -0,0 +1,451 @@
+package poly
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+)
+
+// This file provides binary and JSON (de)serialization for every SyncKeyGen
+// message type, so callers can hand OutMsg values straight to whatever
+// broadcast layer they're driving SyncKeyGen with.
+
+// writePoint and writeSecret length-prefix a marshaled abstract value so it
+// can be read back without knowing the suite's point/secret size in advance.
+func writePoint(buf *bytes.Buffer, p abstract.Point) error {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeBytes(buf, b)
+}
+
+func writeSecret(buf *bytes.Buffer, s abstract.Secret) error {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeBytes(buf, b)
+}
+
+func writeBytes(buf *bytes.Buffer, b []byte) error {
+	if err := binary.Write(buf, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := buf.Write(b)
+	return err
+}
+
+func readBytes(buf *bytes.Reader) ([]byte, error) {
+	var l uint32
+	if err := binary.Read(buf, binary.BigEndian, &l); err != nil {
+		return nil, err
+	}
+	b := make([]byte, l)
+	if _, err := buf.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalBinary encodes a.
+func (a *Ack) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, int32(a.Src))
+	binary.Write(buf, binary.BigEndian, int32(a.PartSrc))
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a.
+func (a *Ack) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewReader(data)
+	var src, partSrc int32
+	if err := binary.Read(buf, binary.BigEndian, &src); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &partSrc); err != nil {
+		return err
+	}
+	a.Src, a.PartSrc = int(src), int(partSrc)
+	return nil
+}
+
+// ackJSON mirrors Ack with exported, json-friendly field names.
+type ackJSON struct {
+	Src     int
+	PartSrc int
+}
+
+// MarshalJSON encodes a.
+func (a *Ack) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ackJSON{a.Src, a.PartSrc})
+}
+
+// UnmarshalJSON decodes a.
+func (a *Ack) UnmarshalJSON(data []byte) error {
+	var j ackJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	a.Src, a.PartSrc = j.Src, j.PartSrc
+	return nil
+}
+
+// MarshalBinary encodes p.
+func (p *Part) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, int32(p.Src))
+	b, err := p.Promise.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBytes(buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes p.
+func (p *Part) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewReader(data)
+	var src int32
+	if err := binary.Read(buf, binary.BigEndian, &src); err != nil {
+		return err
+	}
+	b, err := readBytes(buf)
+	if err != nil {
+		return err
+	}
+	promise := new(Promise)
+	if err := promise.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	p.Src, p.Promise = int(src), promise
+	return nil
+}
+
+// partJSON mirrors Part, with Promise carried as its own marshaled bytes
+// since Promise has no native JSON support.
+type partJSON struct {
+	Src     int
+	Promise []byte
+}
+
+// MarshalJSON encodes p.
+func (p *Part) MarshalJSON() ([]byte, error) {
+	b, err := p.Promise.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(partJSON{p.Src, b})
+}
+
+// UnmarshalJSON decodes p.
+func (p *Part) UnmarshalJSON(data []byte) error {
+	var j partJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	promise := new(Promise)
+	if err := promise.UnmarshalBinary(j.Promise); err != nil {
+		return err
+	}
+	p.Src, p.Promise = j.Src, promise
+	return nil
+}
+
+// MarshalBinary encodes c. Unlike UnmarshalComplaint, this direction needs
+// no suite, since Row already knows how to marshal itself.
+func (c *Complaint) MarshalBinary() ([]byte, error) {
+	if c.Row == nil {
+		return nil, errors.New("Complaint.MarshalBinary : nil Row")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, int32(c.Src))
+	binary.Write(buf, binary.BigEndian, int32(c.PartSrc))
+	if err := writeSecret(buf, c.Row); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalComplaint decodes data produced by Complaint.MarshalBinary.
+// Reconstructing Row needs a suite to call abstract.Suite.Secret() on
+// (abstract.Secret has no default constructor), so unlike Ack.UnmarshalBinary
+// this can't be a method satisfying encoding.BinaryUnmarshaler ; it's a free
+// function instead, the same way UnmarshalOutMsg itself takes a suite.
+func UnmarshalComplaint(suite abstract.Suite, data []byte) (*Complaint, error) {
+	buf := bytes.NewReader(data)
+	var src, partSrc int32
+	if err := binary.Read(buf, binary.BigEndian, &src); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &partSrc); err != nil {
+		return nil, err
+	}
+	b, err := readBytes(buf)
+	if err != nil {
+		return nil, err
+	}
+	row := suite.Secret()
+	if err := row.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return &Complaint{Src: int(src), PartSrc: int(partSrc), Row: row}, nil
+}
+
+// complaintJSON mirrors Complaint, with Row carried as its own marshaled
+// bytes since abstract.Secret has no native JSON support.
+type complaintJSON struct {
+	Src     int
+	PartSrc int
+	Row     []byte
+}
+
+// MarshalJSON encodes c.
+func (c *Complaint) MarshalJSON() ([]byte, error) {
+	if c.Row == nil {
+		return nil, errors.New("Complaint.MarshalJSON : nil Row")
+	}
+	row, err := c.Row.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(complaintJSON{c.Src, c.PartSrc, row})
+}
+
+// UnmarshalComplaintJSON decodes data produced by Complaint.MarshalJSON,
+// using suite to reconstruct Row, for the same reason UnmarshalComplaint
+// can't be a plain UnmarshalJSON method.
+func UnmarshalComplaintJSON(suite abstract.Suite, data []byte) (*Complaint, error) {
+	var j complaintJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	row := suite.Secret()
+	if err := row.UnmarshalBinary(j.Row); err != nil {
+		return nil, err
+	}
+	return &Complaint{Src: j.Src, PartSrc: j.PartSrc, Row: row}, nil
+}
+
+// MarshalBinary encodes j. Unlike UnmarshalJustification, this direction
+// needs no suite, since Row already knows how to marshal itself.
+func (j *Justification) MarshalBinary() ([]byte, error) {
+	if j.Row == nil {
+		return nil, errors.New("Justification.MarshalBinary : nil Row")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, int32(j.PartSrc))
+	binary.Write(buf, binary.BigEndian, int32(j.Against))
+	if err := writeSecret(buf, j.Row); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJustification decodes data produced by Justification.MarshalBinary.
+// Reconstructing Row needs a suite, for the same reason UnmarshalComplaint
+// can't be a plain UnmarshalBinary method.
+func UnmarshalJustification(suite abstract.Suite, data []byte) (*Justification, error) {
+	buf := bytes.NewReader(data)
+	var partSrc, against int32
+	if err := binary.Read(buf, binary.BigEndian, &partSrc); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &against); err != nil {
+		return nil, err
+	}
+	b, err := readBytes(buf)
+	if err != nil {
+		return nil, err
+	}
+	row := suite.Secret()
+	if err := row.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return &Justification{PartSrc: int(partSrc), Against: int(against), Row: row}, nil
+}
+
+// justificationJSON mirrors Justification, with Row carried as its own
+// marshaled bytes since abstract.Secret has no native JSON support.
+type justificationJSON struct {
+	PartSrc int
+	Against int
+	Row     []byte
+}
+
+// MarshalJSON encodes j.
+func (j *Justification) MarshalJSON() ([]byte, error) {
+	if j.Row == nil {
+		return nil, errors.New("Justification.MarshalJSON : nil Row")
+	}
+	row, err := j.Row.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(justificationJSON{j.PartSrc, j.Against, row})
+}
+
+// UnmarshalJustificationJSON decodes data produced by Justification.MarshalJSON,
+// using suite to reconstruct Row, for the same reason UnmarshalJustification
+// can't be a plain UnmarshalJSON method.
+func UnmarshalJustificationJSON(suite abstract.Suite, data []byte) (*Justification, error) {
+	var j justificationJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	row := suite.Secret()
+	if err := row.UnmarshalBinary(j.Row); err != nil {
+		return nil, err
+	}
+	return &Justification{PartSrc: j.PartSrc, Against: j.Against, Row: row}, nil
+}
+
+// MarshalOutMsg encodes msg to binary. suite is not used here : every
+// payload marshals itself without one ; UnmarshalOutMsg is where a suite is
+// needed, to reconstruct a Complaint's or Justification's Row.
+func MarshalOutMsg(suite abstract.Suite, msg *OutMsg) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, int32(msg.Type))
+	var (
+		b   []byte
+		err error
+	)
+	switch msg.Type {
+	case PartMsgType:
+		b, err = msg.Part.MarshalBinary()
+	case AckMsgType:
+		b, err = msg.Ack.MarshalBinary()
+	case ComplaintMsgType:
+		b, err = msg.Complaint.MarshalBinary()
+	case JustifyMsgType:
+		b, err = msg.Justification.MarshalBinary()
+	default:
+		return nil, errors.New(fmt.Sprintf("MarshalOutMsg : unknown message type %d", msg.Type))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBytes(buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalOutMsg decodes data produced by MarshalOutMsg.
+func UnmarshalOutMsg(suite abstract.Suite, data []byte) (*OutMsg, error) {
+	buf := bytes.NewReader(data)
+	var typ int32
+	if err := binary.Read(buf, binary.BigEndian, &typ); err != nil {
+		return nil, err
+	}
+	b, err := readBytes(buf)
+	if err != nil {
+		return nil, err
+	}
+	switch MsgType(typ) {
+	case PartMsgType:
+		part := new(Part)
+		if err := part.UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: PartMsgType, Part: part}, nil
+	case AckMsgType:
+		ack := new(Ack)
+		if err := ack.UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: AckMsgType, Ack: ack}, nil
+	case ComplaintMsgType:
+		c, err := UnmarshalComplaint(suite, b)
+		if err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: ComplaintMsgType, Complaint: c}, nil
+	case JustifyMsgType:
+		j, err := UnmarshalJustification(suite, b)
+		if err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: JustifyMsgType, Justification: j}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("UnmarshalOutMsg : unknown message type %d", typ))
+	}
+}
+
+// outMsgJSON mirrors OutMsg for JSON encoding : exactly one of Part, Ack,
+// Complaint or Justification is populated, holding that payload's own
+// MarshalJSON output, per Type.
+type outMsgJSON struct {
+	Type          MsgType
+	Part          json.RawMessage `json:",omitempty"`
+	Ack           json.RawMessage `json:",omitempty"`
+	Complaint     json.RawMessage `json:",omitempty"`
+	Justification json.RawMessage `json:",omitempty"`
+}
+
+// MarshalOutMsgJSON encodes msg to JSON ; the JSON counterpart to
+// MarshalOutMsg, for callers whose broadcast layer prefers JSON to a raw
+// binary blob.
+func MarshalOutMsgJSON(msg *OutMsg) ([]byte, error) {
+	out := outMsgJSON{Type: msg.Type}
+	var err error
+	switch msg.Type {
+	case PartMsgType:
+		out.Part, err = msg.Part.MarshalJSON()
+	case AckMsgType:
+		out.Ack, err = msg.Ack.MarshalJSON()
+	case ComplaintMsgType:
+		out.Complaint, err = msg.Complaint.MarshalJSON()
+	case JustifyMsgType:
+		out.Justification, err = msg.Justification.MarshalJSON()
+	default:
+		return nil, errors.New(fmt.Sprintf("MarshalOutMsgJSON : unknown message type %d", msg.Type))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalOutMsgJSON decodes data produced by MarshalOutMsgJSON, using
+// suite to reconstruct a Complaint's Row where needed.
+func UnmarshalOutMsgJSON(suite abstract.Suite, data []byte) (*OutMsg, error) {
+	var out outMsgJSON
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	switch out.Type {
+	case PartMsgType:
+		part := new(Part)
+		if err := part.UnmarshalJSON(out.Part); err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: PartMsgType, Part: part}, nil
+	case AckMsgType:
+		ack := new(Ack)
+		if err := ack.UnmarshalJSON(out.Ack); err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: AckMsgType, Ack: ack}, nil
+	case ComplaintMsgType:
+		c, err := UnmarshalComplaintJSON(suite, out.Complaint)
+		if err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: ComplaintMsgType, Complaint: c}, nil
+	case JustifyMsgType:
+		j, err := UnmarshalJustificationJSON(suite, out.Justification)
+		if err != nil {
+			return nil, err
+		}
+		return &OutMsg{Type: JustifyMsgType, Justification: j}, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("UnmarshalOutMsgJSON : unknown message type %d", out.Type))
+	}
+}
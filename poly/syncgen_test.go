@@ -0,0 +1,202 @@
+package poly
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/random"
+)
+
+// generateSyncKeyGenSetup creates n SyncKeyGen instances, each aware of
+// every participant's long-term public key.
+func generateSyncKeyGenSetup(t, n int) ([]*config.KeyPair, []*SyncKeyGen) {
+	suite := edward
+	keys := make([]*config.KeyPair, n)
+	pubs := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		keys[i] = new(config.KeyPair)
+		keys[i].Gen(suite, random.Stream)
+		pubs[i] = keys[i].Public
+	}
+	info := PolyInfo{suite, t, n, n}
+	gens := make([]*SyncKeyGen, n)
+	for i := 0; i < n; i++ {
+		gens[i] = NewSyncKeyGen(info, keys[i], i, pubs)
+	}
+	return keys, gens
+}
+
+func TestSyncKeyGenFullRound(t *testing.T) {
+	n, thresh := 3, 2
+	_, gens := generateSyncKeyGenSetup(thresh, n)
+
+	var parts []*OutMsg
+	for _, g := range gens {
+		out, err := g.Start()
+		if err != nil {
+			t.Error(fmt.Sprintf("Start should not return an error : %v", err))
+		}
+		// out[0] is the Part itself ; the rest is g's own self-Ack(s), which
+		// must be broadcast just like any other node's Ack.
+		parts = append(parts, out[0])
+		for _, other := range gens {
+			for _, selfAck := range out[1:] {
+				if _, err := other.Handle(selfAck); err != nil {
+					t.Error(fmt.Sprintf("Handle(Ack) should not return an error : %v", err))
+				}
+			}
+		}
+	}
+
+	// Deliver every Part, in the same order, to every node (including the
+	// one that produced it, except Start already did that locally).
+	for _, g := range gens {
+		for _, part := range parts {
+			if part.Part.Src == g.index {
+				continue
+			}
+			acks, err := g.Handle(part)
+			if err != nil {
+				t.Error(fmt.Sprintf("Handle(Part) should not return an error : %v", err))
+			}
+			for _, other := range gens {
+				for _, ack := range acks {
+					if _, err := other.Handle(ack); err != nil {
+						t.Error(fmt.Sprintf("Handle(Ack) should not return an error : %v", err))
+					}
+				}
+			}
+		}
+	}
+
+	for _, g := range gens {
+		if !g.IsReady() {
+			t.Error("SyncKeyGen should be ready after a full round of honest Parts/Acks")
+		}
+	}
+
+	secrets := make([]*SharedSecret, n)
+	for i, g := range gens {
+		s, err := g.Finalize()
+		if err != nil {
+			t.Error(fmt.Sprintf("Finalize should not return an error : %v", err))
+		}
+		secrets[i] = s
+	}
+
+	for i := 1; i < n; i++ {
+		if !secrets[0].Pub.Equal(secrets[i].Pub) {
+			t.Error("every node should agree on the same group public polynomial")
+		}
+	}
+}
+
+// TestSyncKeyGenComplaintSerializes drives the complaint path : a node that
+// can't reveal its row of a Part (because it's handed the wrong key) must
+// raise a Complaint carrying a non-nil Row, and that Complaint must survive
+// a full round trip through MarshalOutMsg/UnmarshalOutMsg and
+// MarshalOutMsgJSON/UnmarshalOutMsgJSON without panicking.
+func TestSyncKeyGenComplaintSerializes(t *testing.T) {
+	n, thresh := 3, 2
+	_, gens := generateSyncKeyGenSetup(thresh, n)
+
+	out, err := gens[0].Start()
+	if err != nil {
+		t.Fatal(fmt.Sprintf("Start should not return an error : %v", err))
+	}
+	part := out[0]
+
+	// Swap gens[1]'s key for a fresh, unrelated one so RevealShare fails
+	// when it processes gens[0]'s Part, forcing a Complaint.
+	bad := new(config.KeyPair)
+	bad.Gen(edward, random.Stream)
+	gens[1].key = bad
+
+	outs, err := gens[1].Handle(part)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("Handle(Part) should not return an error even when RevealShare fails : %v", err))
+	}
+	if len(outs) != 1 || outs[0].Type != ComplaintMsgType {
+		t.Fatal("Handle should raise a Complaint when RevealShare fails")
+	}
+	if outs[0].Complaint.Row == nil {
+		t.Fatal("a raised Complaint must carry a non-nil Row")
+	}
+
+	b, err := MarshalOutMsg(edward, outs[0])
+	if err != nil {
+		t.Fatal(fmt.Sprintf("MarshalOutMsg should not return an error : %v", err))
+	}
+	if _, err := UnmarshalOutMsg(edward, b); err != nil {
+		t.Error(fmt.Sprintf("UnmarshalOutMsg should not return an error : %v", err))
+	}
+
+	j, err := MarshalOutMsgJSON(outs[0])
+	if err != nil {
+		t.Fatal(fmt.Sprintf("MarshalOutMsgJSON should not return an error : %v", err))
+	}
+	if _, err := UnmarshalOutMsgJSON(edward, j); err != nil {
+		t.Error(fmt.Sprintf("UnmarshalOutMsgJSON should not return an error : %v", err))
+	}
+}
+
+// TestSyncKeyGenJustifyClearsHonestDealer drives a fabricated Complaint
+// against an honest dealer : the accused dealer's own Justification must
+// check out and the Part must stay trusted, instead of the complainer's
+// unsubstantiated claim getting it excluded. The Justification must also
+// survive a full round trip through both serialization formats.
+func TestSyncKeyGenJustifyClearsHonestDealer(t *testing.T) {
+	n, thresh := 3, 2
+	suite := edward
+	_, gens := generateSyncKeyGenSetup(thresh, n)
+
+	out, err := gens[0].Start()
+	if err != nil {
+		t.Fatal(fmt.Sprintf("Start should not return an error : %v", err))
+	}
+	part := out[0]
+	if _, err := gens[0].Handle(part); err == nil {
+		t.Fatal("Handle should reject a second Part from the same Src")
+	}
+
+	// gens[1] receives gens[0]'s Part, then fabricates a Complaint against
+	// the honest gens[0], with a bogus Row that doesn't match what it
+	// actually received.
+	if _, err := gens[1].Handle(part); err != nil {
+		t.Fatal(fmt.Sprintf("Handle(Part) should not return an error : %v", err))
+	}
+	bogus := &Complaint{Src: 1, PartSrc: 0, Row: suite.Secret().Pick(random.Stream)}
+	outs, err := gens[0].Handle(&OutMsg{Type: ComplaintMsgType, Complaint: bogus})
+	if err != nil {
+		t.Fatal(fmt.Sprintf("Handle(Complaint) should not return an error : %v", err))
+	}
+	if len(outs) != 1 || outs[0].Type != JustifyMsgType {
+		t.Fatal("the accused dealer should answer a Complaint with a Justification")
+	}
+
+	b, err := MarshalOutMsg(suite, outs[0])
+	if err != nil {
+		t.Fatal(fmt.Sprintf("MarshalOutMsg should not return an error : %v", err))
+	}
+	roundTripped, err := UnmarshalOutMsg(suite, b)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("UnmarshalOutMsg should not return an error : %v", err))
+	}
+
+	jb, err := MarshalOutMsgJSON(outs[0])
+	if err != nil {
+		t.Fatal(fmt.Sprintf("MarshalOutMsgJSON should not return an error : %v", err))
+	}
+	if _, err := UnmarshalOutMsgJSON(suite, jb); err != nil {
+		t.Error(fmt.Sprintf("UnmarshalOutMsgJSON should not return an error : %v", err))
+	}
+
+	if _, err := gens[1].Handle(roundTripped); err != nil {
+		t.Fatal(fmt.Sprintf("Handle(Justification) should not return an error : %v", err))
+	}
+	if gens[1].excluded[0] {
+		t.Error("an honest dealer's own Justification should clear it, not get it excluded")
+	}
+}
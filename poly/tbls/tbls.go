@@ -0,0 +1,126 @@
+// Package tbls implements threshold BLS signature aggregation on top of a
+// poly.SharedSecret. Once a group of nodes holds shares of a common secret
+// key (e.g. via Receiver.ProduceSharedSecret or poly.SyncKeyGen.Finalize),
+// each of them can Sign a message with its own share ; any t resulting
+// PartialSigs can then be combined with Recover into a single, regular BLS
+// signature over the group's public key, without ever reconstructing the
+// group secret key itself.
+package tbls
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/poly"
+)
+
+// Suite is what tbls needs on top of the usual abstract.Suite primitives :
+// a way to hash a message onto the pairing group BLS signatures live in,
+// and the bilinear pairing itself. A suite built on e.g. bn256 satisfies
+// this.
+type Suite interface {
+	abstract.Suite
+	// HashToPoint deterministically maps msg onto the signature group.
+	HashToPoint(msg []byte) abstract.Point
+	// Pair evaluates the bilinear pairing e(a, b), used to check signatures.
+	Pair(a, b abstract.Point) abstract.Point
+}
+
+// PartialSig is one node's threshold BLS signature share : msg signed with
+// the node's share of the group secret key, tagged with the share's index
+// so Recover knows which Lagrange coefficient to apply to it.
+type PartialSig struct {
+	Index int
+	Sig   []byte
+}
+
+// Sign produces ss's partial BLS signature over msg : sig = H(msg)^share.
+func Sign(suite Suite, ss *poly.SharedSecret, msg []byte) (*PartialSig, error) {
+	sig := suite.Point().Mul(suite.HashToPoint(msg), *ss.Share)
+	b, err := sig.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &PartialSig{Index: ss.Index, Sig: b}, nil
+}
+
+// Verify checks ps against pub, the group's public polynomial, letting
+// callers identify and slash misbehaving signers : it evaluates pub at
+// ps.Index to get that signer's individual public key, and checks ps.Sig
+// against it the same way Recover checks the aggregate signature against
+// the group key.
+func Verify(suite Suite, pub *poly.PubPoly, msg []byte, ps *PartialSig) error {
+	return verify(suite, pub.Eval(ps.Index), msg, ps.Sig)
+}
+
+// Recover combines every one of sigs, of which there must be at least t
+// (out of the n participants described by pub), into a single BLS
+// signature over msg, verifies it against the group public key, and
+// returns it.
+func Recover(suite Suite, pub *poly.PubPoly, msg []byte, sigs []*PartialSig, t, n int) ([]byte, error) {
+	if len(sigs) < t {
+		return nil, errors.New(fmt.Sprintf("Recover : got %d partial signatures, need at least %d", len(sigs), t))
+	}
+
+	// Share indices are 0-based, while lagrangeAt0 works on 1-based
+	// x-coordinates : share Index is the polynomial's evaluation at Index+1.
+	present := make([]int, len(sigs))
+	for i, ps := range sigs {
+		present[i] = ps.Index + 1
+	}
+
+	acc := suite.Point().Null()
+	for _, ps := range sigs {
+		p := suite.Point()
+		if err := p.UnmarshalBinary(ps.Sig); err != nil {
+			return nil, errors.New(fmt.Sprintf("Recover : could not decode partial signature %d : %v", ps.Index, err))
+		}
+		l := lagrangeAt0(suite, ps.Index+1, present)
+		acc.Add(acc, suite.Point().Mul(p, l))
+	}
+
+	b, err := acc.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(suite, pub.SecretCommit(), msg, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// verify checks sig against msg and pubKey via e(sig, G2) == e(H(msg), pubKey).
+func verify(suite Suite, pubKey abstract.Point, msg []byte, sig []byte) error {
+	s := suite.Point()
+	if err := s.UnmarshalBinary(sig); err != nil {
+		return errors.New(fmt.Sprintf("verify : could not decode signature : %v", err))
+	}
+	h := suite.HashToPoint(msg)
+
+	lhs := suite.Pair(s, suite.Point().Base())
+	rhs := suite.Pair(h, pubKey)
+	if !lhs.Equal(rhs) {
+		return errors.New("verify : signature does not match the given public key")
+	}
+	return nil
+}
+
+// lagrangeAt0 computes the Lagrange coefficient L_i(0) for index i (1-based)
+// with respect to the other indices in present (also 1-based). It mirrors
+// poly's unexported helper of the same name, since tbls lives in its own
+// package and combines shares the same way poly.PVSSDeal.Recover does.
+func lagrangeAt0(suite abstract.Suite, i int, present []int) abstract.Secret {
+	num := suite.Secret().One()
+	den := suite.Secret().One()
+	xi := suite.Secret().SetInt64(int64(i))
+	for _, j := range present {
+		if j == i {
+			continue
+		}
+		xj := suite.Secret().SetInt64(int64(j))
+		num.Mul(num, xj)
+		den.Mul(den, suite.Secret().Sub(xj, xi))
+	}
+	return num.Div(num, den)
+}
@@ -0,0 +1,188 @@
+package tbls
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/crypto/abstract"
+	"github.com/dedis/crypto/config"
+	"github.com/dedis/crypto/edwards"
+	"github.com/dedis/crypto/poly"
+	"github.com/dedis/crypto/random"
+)
+
+// fakePoint wraps a real edwards point and additionally tracks its own
+// discrete log relative to the group generator, in cleartext, plus recalls
+// it across a MarshalBinary/UnmarshalBinary round trip via fakeSuite's
+// registry. No real curve lets anyone but a point's own creator do this ;
+// it only works here because every point this test ever Pairs was created
+// by fakeSuite in the first place, in the same process. See fakeSuite.
+type fakePoint struct {
+	abstract.Point
+	suite    abstract.Suite
+	registry map[string]abstract.Secret
+	log      abstract.Secret
+}
+
+func (p *fakePoint) Null() abstract.Point {
+	p.Point = p.Point.Null()
+	p.log = p.suite.Secret().Zero()
+	return p
+}
+
+func (p *fakePoint) Base() abstract.Point {
+	p.Point = p.Point.Base()
+	p.log = p.suite.Secret().One()
+	return p
+}
+
+func (p *fakePoint) Add(a, b abstract.Point) abstract.Point {
+	fa, fb := a.(*fakePoint), b.(*fakePoint)
+	p.Point = p.Point.Add(fa.Point, fb.Point)
+	p.log = p.suite.Secret().Add(fa.log, fb.log)
+	return p
+}
+
+func (p *fakePoint) Mul(base abstract.Point, s abstract.Secret) abstract.Point {
+	// Mul(nil, s) conventionally means "multiply the generator by s".
+	baseLog := p.suite.Secret().One()
+	var innerBase abstract.Point
+	if base != nil {
+		fb := base.(*fakePoint)
+		innerBase, baseLog = fb.Point, fb.log
+	}
+	p.Point = p.Point.Mul(innerBase, s)
+	p.log = p.suite.Secret().Mul(baseLog, s)
+	return p
+}
+
+// MarshalBinary encodes p, stashing its log in the shared registry under
+// the encoded bytes so a later UnmarshalBinary elsewhere (e.g. after a
+// PartialSig round-trips through Sig []byte) can recall it.
+func (p *fakePoint) MarshalBinary() ([]byte, error) {
+	b, err := p.Point.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if p.log != nil {
+		p.registry[string(b)] = p.log
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes p, recovering its log from the registry if this
+// test previously marshaled these exact bytes.
+func (p *fakePoint) UnmarshalBinary(data []byte) error {
+	if err := p.Point.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	p.log = p.registry[string(data)]
+	return nil
+}
+
+// fakeSuite adapts a real, non pairing-capable edwards suite into a
+// tbls.Suite for testing : it embeds the real suite for every ordinary
+// abstract.Suite operation (Secret, Cipher, KeyPair generation, ...) and
+// fakes HashToPoint/Pair on top of fakePoint's log-tracking, since
+// edwards25519 supports no genuine bilinear pairing. A production tbls.Suite
+// needs an actual pairing-friendly curve instead ; this exists only so the
+// test below can exercise Sign/Verify/Recover's real pairing check.
+type fakeSuite struct {
+	abstract.Suite
+	registry map[string]abstract.Secret
+}
+
+func newFakeSuite() *fakeSuite {
+	return &fakeSuite{
+		Suite:    edwards.NewAES128SHA256Ed25519(),
+		registry: make(map[string]abstract.Secret),
+	}
+}
+
+// Point returns a fresh, trackable point wrapping a real one.
+func (f *fakeSuite) Point() abstract.Point {
+	return &fakePoint{Point: f.Suite.Point(), suite: f, registry: f.registry}
+}
+
+// HashToPoint derives a point deterministically from msg, the same way
+// dleqChallenge derives a scalar from a transcript elsewhere in poly : seed
+// a cipher with msg, then pick a scalar from it and multiply the generator.
+func (f *fakeSuite) HashToPoint(msg []byte) abstract.Point {
+	exp := f.Suite.Secret().Pick(f.Suite.Cipher(msg))
+	return f.Point().Mul(nil, exp)
+}
+
+// Pair fakes e(a, b) as g^(log(a)*log(b)), which only typechecks/works
+// because every point this test ever hands to Pair was created by this same
+// fakeSuite, so its log is always known (see fakePoint).
+func (f *fakeSuite) Pair(a, b abstract.Point) abstract.Point {
+	fa, fb := a.(*fakePoint), b.(*fakePoint)
+	exp := f.Suite.Secret().Mul(fa.log, fb.log)
+	return f.Point().Mul(nil, exp)
+}
+
+var suite = newFakeSuite()
+
+// generateTBLSSetup runs an ordinary (t, n) Pedersen DKG to produce n
+// SharedSecrets of a common group key, the same way poly's own tests do.
+func generateTBLSSetup(t, n int) (*poly.PubPoly, []*poly.SharedSecret) {
+	keys := make([]*config.KeyPair, n)
+	pubs := make([]abstract.Point, n)
+	for i := 0; i < n; i++ {
+		keys[i] = new(config.KeyPair)
+		keys[i].Gen(suite, random.Stream)
+		pubs[i] = keys[i].Public
+	}
+
+	info := poly.PolyInfo{Suite: suite, T: t, R: t, N: n}
+	dealers := make([]*poly.Dealer, n)
+	for i := range keys {
+		dealers[i] = poly.NewDealer(info, keys[i], keys[i], pubs)
+	}
+
+	shares := make([]*poly.SharedSecret, n)
+	for i := 0; i < n; i++ {
+		r := poly.NewReceiver(info, keys[i])
+		for _, d := range dealers {
+			resp, err := r.AddDealer(i, d)
+			if err != nil {
+				panic(err)
+			}
+			if err := d.AddResponse(i, resp); err != nil {
+				panic(err)
+			}
+		}
+		s, err := r.ProduceSharedSecret()
+		if err != nil {
+			panic(err)
+		}
+		shares[i] = s
+	}
+	return shares[0].Pub, shares
+}
+
+func TestSignVerifyRecover(t *testing.T) {
+	thresh, n := 3, 5
+	pub, shares := generateTBLSSetup(thresh, n)
+	msg := []byte("threshold bls round trip")
+
+	sigs := make([]*PartialSig, 0, thresh)
+	for i := 0; i < thresh; i++ {
+		ps, err := Sign(suite, shares[i], msg)
+		if err != nil {
+			t.Fatal(fmt.Sprintf("Sign should not return an error : %v", err))
+		}
+		if err := Verify(suite, pub, msg, ps); err != nil {
+			t.Error(fmt.Sprintf("Verify should accept a genuine partial signature : %v", err))
+		}
+		sigs = append(sigs, ps)
+	}
+
+	sig, err := Recover(suite, pub, msg, sigs, thresh, n)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("Recover should not return an error : %v", err))
+	}
+	if len(sig) == 0 {
+		t.Error("Recover should return a non-empty signature")
+	}
+}